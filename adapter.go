@@ -1,28 +1,35 @@
 /*
    This package is responsible for converting ? placeholders into
-   $i placeholders, like MySQL to PostgesQL.
+   whatever placeholder syntax the current Dialect expects (e.g. $i
+   for postgres, left as ? for mysql/sqlite3).
 */
 
 package modeldb
 
 import (
-	"fmt"
 	. "github.com/jaekwon/pego"
 	"strings"
 )
 
-var phConversions = map[string]string{}
+var phConversions = map[string]map[string]string{}
 
 var phGrammar = Grm("S", map[string]*Pattern{
 	"S": Seq(
 		Ref("OTHER"),
 		Seq(
-			Ref("PH").Or(Ref("STR")),
+			Ref("PH").Or(Ref("NAMED")).Or(Ref("STR")),
 			Ref("OTHER"),
 		).Rep(0, -1),
 	).Clist(),
-	"OTHER": NegSet("'?").Rep(0, -1).Csimple(),
+	"OTHER": NegSet("'?:").Rep(0, -1).Csimple(),
 	"PH":    Char('?').Csimple(),
+	// A :name token, e.g. :user_id. Doesn't match a ':' inside a string
+	// literal, since OTHER stops at the opening quote and STR then consumes
+	// the whole literal.
+	"NAMED": Seq(
+		Char(':'),
+		Set("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_").Rep(1, -1),
+	).Csimple(),
 	"STR": Seq(
 		Char('\''),
 		Seq(
@@ -33,12 +40,12 @@ var phGrammar = Grm("S", map[string]*Pattern{
 	).Csimple(),
 })
 
-func ReplacePH(items []interface{}) string {
+func ReplacePH(items []interface{}, dialect Dialect) string {
 	index := 1
 	parts := []string{}
 	for _, item := range items {
 		if item == "?" {
-			parts = append(parts, fmt.Sprintf("$%v", index))
+			parts = append(parts, dialect.Placeholder(index))
 			index++
 		} else {
 			parts = append(parts, item.(string))
@@ -48,13 +55,23 @@ func ReplacePH(items []interface{}) string {
 	return strings.Join(parts, "")
 }
 
-func ConvertPH(q string) string {
-	if phConversions[q] != "" {
-		return phConversions[q]
+// ConvertPH rewrites the ? placeholders in q into dialect's placeholder
+// syntax, leaving ? inside string literals untouched. Results are cached
+// per-dialect since the same query is normally re-run many times.
+func ConvertPH(q string, dialect Dialect) string {
+	cache, ok := phConversions[dialect.Name()]
+	if !ok {
+		cache = map[string]string{}
+		phConversions[dialect.Name()] = cache
+	}
+	if converted, ok := cache[q]; ok {
+		return converted
 	}
 	r, err, _ := Match(phGrammar, q)
 	if err != nil {
 		panic(err)
 	}
-	return ReplacePH(r.([]interface{}))
+	converted := ReplacePH(r.([]interface{}), dialect)
+	cache[q] = converted
+	return converted
 }
@@ -0,0 +1,87 @@
+package modeldb
+
+import "testing"
+
+func TestConvertPH(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		dialect Dialect
+		want    string
+	}{
+		{"postgres renumbers", "SELECT * FROM t WHERE a = ? AND b = ?", NewPostgresDialect(), "SELECT * FROM t WHERE a = $1 AND b = $2"},
+		{"mysql stays ?", "SELECT * FROM t WHERE a = ? AND b = ?", NewMySQLDialect(), "SELECT * FROM t WHERE a = ? AND b = ?"},
+		{"ignores ? inside string literal", "SELECT * FROM t WHERE a = ? AND b = 'what?'", NewPostgresDialect(), "SELECT * FROM t WHERE a = $1 AND b = 'what?'"},
+		{"no placeholders", "SELECT 1", NewPostgresDialect(), "SELECT 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConvertPH(tt.query, tt.dialect); got != tt.want {
+				t.Errorf("ConvertPH(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertPHCachesPerDialect(t *testing.T) {
+	q := "SELECT * FROM t WHERE a = ?"
+	pg := ConvertPH(q, NewPostgresDialect())
+	my := ConvertPH(q, NewMySQLDialect())
+	if pg == my {
+		t.Errorf("expected different conversions per dialect, got %q for both", pg)
+	}
+}
+
+func TestConvertNamedPH(t *testing.T) {
+	binds := map[string]interface{}{"id": 1, "name": "bob"}
+
+	q, args, err := convertNamedPH("SELECT * FROM t WHERE id = :id AND name = :name", NewPostgresDialect(), binds)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "SELECT * FROM t WHERE id = $1 AND name = $2"; q != want {
+		t.Errorf("query = %q, want %q", q, want)
+	}
+	if len(args) != 2 || args[0] != 1 || args[1] != "bob" {
+		t.Errorf("args = %v, want [1 bob]", args)
+	}
+}
+
+func TestConvertNamedPHMissingBind(t *testing.T) {
+	_, _, err := convertNamedPH("SELECT * FROM t WHERE id = :id", NewPostgresDialect(), map[string]interface{}{})
+	if err == nil {
+		t.Fatal("expected an error for an unbound :name token, got nil")
+	}
+}
+
+func TestConvertNamedPHRejectsBarePlaceholder(t *testing.T) {
+	binds := map[string]interface{}{"id": 1}
+	// Named queries take all their values from binds; NamedExec/NamedQuery/
+	// NamedQueryRow have no positional-args parameter to supply a value
+	// for a bare ?, so mixing the two is rejected rather than silently
+	// emitting a placeholder with no matching arg.
+	_, _, err := convertNamedPH("SELECT * FROM t WHERE a = ? AND id = :id", NewMySQLDialect(), binds)
+	if err == nil {
+		t.Fatal("expected an error for a bare ? placeholder in a named query, got nil")
+	}
+}
+
+type bindsForModel struct {
+	ID   int64  `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+func TestBindsForStruct(t *testing.T) {
+	binds := bindsFor(&bindsForModel{ID: 1, Name: "bob"})
+	if binds["id"] != int64(1) || binds["name"] != "bob" {
+		t.Errorf("binds = %v, want map with id=1 name=bob", binds)
+	}
+}
+
+func TestBindsForMap(t *testing.T) {
+	in := map[string]interface{}{"id": 1}
+	binds := bindsFor(in)
+	if binds["id"] != 1 {
+		t.Errorf("binds = %v, want map passed through unchanged", binds)
+	}
+}
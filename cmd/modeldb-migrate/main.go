@@ -0,0 +1,100 @@
+/*
+modeldb-migrate is a thin CLI wrapper around the migrate package, in the
+spirit of goose:
+
+	modeldb-migrate -dsn "$DATABASE_URL" -dir ./migrations up
+	modeldb-migrate -dsn "$DATABASE_URL" -dir ./migrations down
+	modeldb-migrate -dsn "$DATABASE_URL" -dir ./migrations status
+
+Production binaries will usually embed their migrations directory with
+embed.FS and call migrate.CollectMigrations directly instead of shelling
+out to this binary; it's provided for local development and ops scripts.
+*/
+
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/jaekwon/go-modeldb"
+	"github.com/jaekwon/go-modeldb/migrate"
+)
+
+func main() {
+	dsn := flag.String("dsn", os.Getenv("DATABASE_URL"), "database connection string")
+	dir := flag.String("dir", "migrations", "directory containing .sql migrations")
+	dialect := flag.String("dialect", "postgres", "postgres | mysql | sqlite3")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: modeldb-migrate [-dsn ...] [-dir ...] [-dialect ...] up|down|redo|status")
+		os.Exit(1)
+	}
+
+	db, err := sql.Open(*dialect, *dsn)
+	if err != nil {
+		fatal(err)
+	}
+	modeldb.SetDB(db)
+	modeldb.SetDialect(dialectFor(*dialect))
+
+	migrations, err := migrate.CollectMigrations(os.DirFS(*dir), ".")
+	if err != nil {
+		fatal(err)
+	}
+
+	switch flag.Arg(0) {
+	case "up":
+		err = migrate.Up(migrations)
+	case "down":
+		err = migrate.Down(migrations)
+	case "redo":
+		err = migrate.Redo(migrations)
+	case "status":
+		err = printStatus(migrations)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		os.Exit(1)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func printStatus(migrations []*migrate.Migration) error {
+	statuses, err := migrate.GetStatus(migrations)
+	if err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		mark := " "
+		if s.Applied {
+			mark = "x"
+		}
+		fmt.Printf("[%v] %v_%v\n", mark, s.Version, s.Name)
+	}
+	return nil
+}
+
+func dialectFor(name string) modeldb.Dialect {
+	switch name {
+	case "mysql":
+		return modeldb.NewMySQLDialect()
+	case "sqlite3":
+		return modeldb.NewSQLite3Dialect()
+	default:
+		return modeldb.NewPostgresDialect()
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,143 @@
+/*
+This file adds context.Context-aware variants of Exec/Query/QueryRow/
+QueryAll, so an HTTP handler (or anything else with a request-scoped
+deadline) can cancel a query or abort DoBegin's retry loop instead of
+blocking indefinitely. See BeginContext/DoBeginContext in modeldb.go for
+the transaction side of this.
+*/
+
+package modeldb
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+)
+
+// ConnContext is Conn, plus the context-aware methods *sql.DB and *sql.Tx
+// already provide.
+type ConnContext interface {
+	Conn
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// MConnContext is MConn, plus context-aware variants, implemented by
+// *ModelDB and *ModelTx.
+type MConnContext interface {
+	MConn
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*ModelRows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *ModelRow
+	QueryAllContext(ctx context.Context, proto interface{}, query string, args ...interface{}) (interface{}, error)
+}
+
+func _ExecContext(ctx context.Context, c ConnContext, query string, args ...interface{}) (sql.Result, error) {
+	q, a := ConvertPH(query, GetDialect()), expandArgs(args...)
+	beforeQuery(ctx, q, a)
+	start := time.Now()
+	res, err := c.ExecContext(ctx, q, a...)
+	logQuery(ctx, q, a, start, err)
+	return res, err
+}
+
+func _QueryRowContext(ctx context.Context, c ConnContext, query string, args ...interface{}) *ModelRow {
+	q, a := ConvertPH(query, GetDialect()), expandArgs(args...)
+	beforeQuery(ctx, q, a)
+	start := time.Now()
+	row := c.QueryRowContext(ctx, q, a...)
+	logQuery(ctx, q, a, start, nil)
+	return &ModelRow{row}
+}
+
+func _QueryContext(ctx context.Context, c ConnContext, query string, args ...interface{}) (*ModelRows, error) {
+	q, a := ConvertPH(query, GetDialect()), expandArgs(args...)
+	beforeQuery(ctx, q, a)
+	start := time.Now()
+	rows, err := c.QueryContext(ctx, q, a...)
+	logQuery(ctx, q, a, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelRows{rows}, nil
+}
+
+func _QueryAllContext(ctx context.Context, c ConnContext, proto interface{}, query string, args ...interface{}) (interface{}, error) {
+	protos := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(reflect.TypeOf(proto))), 0, 0)
+	q, a := ConvertPH(query, GetDialect()), expandArgs(args...)
+	beforeQuery(ctx, q, a)
+	start := time.Now()
+	rows, err := c.QueryContext(ctx, q, a...)
+	logQuery(ctx, q, a, start, err)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		protoValueP := reflect.New(reflect.TypeOf(proto))
+		err := scanStruct(rows, protoValueP.Interface())
+		if err != nil {
+			return nil, err
+		}
+		protos = reflect.Append(protos, protoValueP)
+	}
+	return protos.Interface(), nil
+}
+
+//-----------------------------------------------------------------------------
+// ModelDB
+
+func (mDB *ModelDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return _ExecContext(ctx, mDB.DB, query, args...)
+}
+
+func (mDB *ModelDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*ModelRows, error) {
+	return _QueryContext(ctx, mDB.DB, query, args...)
+}
+
+func (mDB *ModelDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *ModelRow {
+	return _QueryRowContext(ctx, mDB.DB, query, args...)
+}
+
+func (mDB *ModelDB) QueryAllContext(ctx context.Context, proto interface{}, query string, args ...interface{}) (interface{}, error) {
+	return _QueryAllContext(ctx, mDB.DB, proto, query, args...)
+}
+
+//-----------------------------------------------------------------------------
+// ModelTx
+
+func (mTx *ModelTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return _ExecContext(ctx, mTx.Tx, query, args...)
+}
+
+func (mTx *ModelTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*ModelRows, error) {
+	return _QueryContext(ctx, mTx.Tx, query, args...)
+}
+
+func (mTx *ModelTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *ModelRow {
+	return _QueryRowContext(ctx, mTx.Tx, query, args...)
+}
+
+func (mTx *ModelTx) QueryAllContext(ctx context.Context, proto interface{}, query string, args ...interface{}) (interface{}, error) {
+	return _QueryAllContext(ctx, mTx.Tx, proto, query, args...)
+}
+
+//-----------------------------------------------------------------------------
+// Convenience methods
+
+func ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return _ExecContext(ctx, GetDB(), query, args...)
+}
+
+func QueryRowContext(ctx context.Context, query string, args ...interface{}) *ModelRow {
+	return _QueryRowContext(ctx, GetDB(), query, args...)
+}
+
+func QueryContext(ctx context.Context, query string, args ...interface{}) (*ModelRows, error) {
+	return _QueryContext(ctx, GetDB(), query, args...)
+}
+
+func QueryAllContext(ctx context.Context, proto interface{}, query string, args ...interface{}) (interface{}, error) {
+	return _QueryAllContext(ctx, GetDB(), proto, query, args...)
+}
@@ -0,0 +1,140 @@
+package modeldb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// fakeConnContext records the ctx/query/args passed to the *Context
+// methods. The non-context Conn methods aren't exercised here.
+type fakeConnContext struct {
+	ctx   context.Context
+	query string
+	args  []interface{}
+}
+
+func (c *fakeConnContext) Exec(query string, args ...interface{}) (sql.Result, error) {
+	panic("not used in these tests")
+}
+func (c *fakeConnContext) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	panic("not used in these tests")
+}
+func (c *fakeConnContext) QueryRow(query string, args ...interface{}) *sql.Row {
+	panic("not used in these tests")
+}
+
+func (c *fakeConnContext) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.ctx, c.query, c.args = ctx, query, args
+	return fakeResult{lastID: 1}, nil
+}
+func (c *fakeConnContext) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.ctx, c.query, c.args = ctx, query, args
+	return nil, nil
+}
+func (c *fakeConnContext) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.ctx, c.query, c.args = ctx, query, args
+	return nil
+}
+
+func TestExecContextPropagatesCtxAndConvertsPlaceholders(t *testing.T) {
+	origBefore, origAfter := BeforeQuery, AfterQuery
+	t.Cleanup(func() { BeforeQuery, AfterQuery = origBefore, origAfter })
+
+	var beforeCtx, afterCtx context.Context
+	BeforeQuery = func(ctx context.Context, query string, args []interface{}) { beforeCtx = ctx }
+	AfterQuery = func(ctx context.Context, query string, args []interface{}, d time.Duration, err error) {
+		afterCtx = ctx
+	}
+
+	type key string
+	ctx := context.WithValue(context.Background(), key("k"), "v")
+	c := &fakeConnContext{}
+
+	if _, err := _ExecContext(ctx, c, "INSERT INTO t VALUES (?)", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.ctx != ctx {
+		t.Error("_ExecContext did not pass ctx through to c.ExecContext")
+	}
+	if want := "INSERT INTO t VALUES ($1)"; c.query != want {
+		t.Errorf("query = %q, want %q", c.query, want)
+	}
+	if beforeCtx != ctx || afterCtx != ctx {
+		t.Error("BeforeQuery/AfterQuery hooks did not receive the caller's ctx")
+	}
+}
+
+func TestQueryRowContextPropagatesCtx(t *testing.T) {
+	ctx := context.Background()
+	c := &fakeConnContext{}
+	_QueryRowContext(ctx, c, "SELECT 1 FROM t WHERE id = ?", 7)
+	if c.ctx != ctx {
+		t.Error("_QueryRowContext did not pass ctx through to c.QueryRowContext")
+	}
+	if want := "SELECT 1 FROM t WHERE id = $1"; c.query != want {
+		t.Errorf("query = %q, want %q", c.query, want)
+	}
+}
+
+//-----------------------------------------------------------------------------
+// DoBeginContext retry-abort, against a minimal fake database/sql/driver.
+
+type fakeTxDriver struct{}
+
+func (fakeTxDriver) Open(name string) (driver.Conn, error) { return &fakeTxConn{}, nil }
+
+type fakeTxConn struct{}
+
+func (c *fakeTxConn) Prepare(query string) (driver.Stmt, error) { return &fakeTxStmt{}, nil }
+func (c *fakeTxConn) Close() error                              { return nil }
+func (c *fakeTxConn) Begin() (driver.Tx, error)                 { return &fakeTxTx{}, nil }
+
+type fakeTxTx struct{}
+
+func (fakeTxTx) Commit() error   { return nil }
+func (fakeTxTx) Rollback() error { return nil }
+
+type fakeTxStmt struct{}
+
+func (fakeTxStmt) Close() error  { return nil }
+func (fakeTxStmt) NumInput() int { return -1 }
+func (fakeTxStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.ResultNoRows, nil
+}
+func (fakeTxStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestDoBeginContextAbortsRetryOnCanceledContext(t *testing.T) {
+	sql.Register("modeldb-faketx-driver", fakeTxDriver{})
+	db, err := sql.Open("modeldb-faketx-driver", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	origDB, origDialect := GetDB(), GetDialect()
+	SetDB(db)
+	SetDialect(NewPostgresDialect())
+	t.Cleanup(func() { SetDB(origDB); SetDialect(origDialect) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tries := 0
+	err = DoBeginContext(ctx, "", func(tx *ModelTx) {
+		tries++
+		// Simulate ctx being canceled (e.g. the request deadline firing)
+		// while f is mid-flight, then f failing with a serialization error.
+		cancel()
+		panic(&pq.Error{Code: "40001"})
+	})
+
+	if tries != 1 {
+		t.Errorf("f ran %v times, want exactly 1 (no retry after ctx is canceled)", tries)
+	}
+	if err != ctx.Err() {
+		t.Errorf("DoBeginContext returned %v, want ctx.Err() (%v)", err, ctx.Err())
+	}
+}
@@ -0,0 +1,280 @@
+/*
+This file adds struct-driven CRUD helpers on top of ModelInfo: Insert,
+Update, Delete, FindByPK, and Upsert. They synthesize SQL the same way
+hand-written queries would, using fields tagged "pk" to identify
+WHERE/ON CONFLICT targets, "autoinc" to read back a generated id after
+Insert, "unique" as Upsert's conflict target when no "pk" field exists,
+and "default=..." to omit zero-valued columns from Insert so the
+database's own DEFAULT applies.
+*/
+
+package modeldb
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+)
+
+func _Insert(c MConn, dialect Dialect, model interface{}) error {
+	m := GetModelInfo(model)
+	if m == nil {
+		log.Panicf("Insert argument must be a struct, got %T", model)
+	}
+
+	autoincCol := ""
+	if m.AutoincField != nil {
+		autoincCol = m.AutoincField.Column
+	}
+
+	cols := []string{}
+	phs := []string{}
+	args := []interface{}{}
+	ph := 1
+	for _, field := range m.Fields {
+		if field.Autoinc {
+			continue
+		}
+		// Fields tagged "default=..." with a zero Go value are left out of
+		// the INSERT entirely, so the database's own DEFAULT applies
+		// instead of an explicit zero value.
+		if field.Default != "" && isFieldZero(model, field) {
+			continue
+		}
+		cols = append(cols, field.Column)
+		phs = append(phs, dialect.Placeholder(ph))
+		args = append(args, m.FieldValue(model, field))
+		ph++
+	}
+
+	query := fmt.Sprintf("INSERT INTO %v (%v) VALUES (%v)%v",
+		m.TableName, strings.Join(cols, ", "), strings.Join(phs, ", "), dialect.ReturningClause(autoincCol))
+
+	if m.AutoincField == nil {
+		_, err := c.Exec(query, args...)
+		return err
+	}
+
+	if dialect.SupportsReturning() {
+		var id int64
+		if err := c.QueryRow(query, args...).Scan(&id); err != nil {
+			return err
+		}
+		setFieldInt(model, m.AutoincField, id)
+		return nil
+	}
+
+	res, err := c.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	setFieldInt(model, m.AutoincField, id)
+	return nil
+}
+
+func _Update(c MConn, dialect Dialect, model interface{}) error {
+	m := GetModelInfo(model)
+	if m == nil {
+		log.Panicf("Update argument must be a struct, got %T", model)
+	}
+	if len(m.PKFields) == 0 {
+		log.Panicf(`Update requires at least one field tagged "pk" on %v`, m.Type)
+	}
+
+	sets := []string{}
+	args := []interface{}{}
+	ph := 1
+	for _, field := range m.Fields {
+		if field.PK || field.Autoinc {
+			continue
+		}
+		sets = append(sets, fmt.Sprintf("%v = %v", field.Column, dialect.Placeholder(ph)))
+		args = append(args, m.FieldValue(model, field))
+		ph++
+	}
+
+	wheres := []string{}
+	for _, field := range m.PKFields {
+		wheres = append(wheres, fmt.Sprintf("%v = %v", field.Column, dialect.Placeholder(ph)))
+		args = append(args, m.FieldValue(model, field))
+		ph++
+	}
+
+	query := fmt.Sprintf("UPDATE %v SET %v WHERE %v",
+		m.TableName, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
+	_, err := c.Exec(query, args...)
+	return err
+}
+
+func _Delete(c MConn, dialect Dialect, model interface{}) error {
+	m := GetModelInfo(model)
+	if m == nil {
+		log.Panicf("Delete argument must be a struct, got %T", model)
+	}
+	if len(m.PKFields) == 0 {
+		log.Panicf(`Delete requires at least one field tagged "pk" on %v`, m.Type)
+	}
+
+	wheres := []string{}
+	args := []interface{}{}
+	for i, field := range m.PKFields {
+		wheres = append(wheres, fmt.Sprintf("%v = %v", field.Column, dialect.Placeholder(i+1)))
+		args = append(args, m.FieldValue(model, field))
+	}
+
+	query := fmt.Sprintf("DELETE FROM %v WHERE %v", m.TableName, strings.Join(wheres, " AND "))
+	_, err := c.Exec(query, args...)
+	return err
+}
+
+func _FindByPK(c MConn, dialect Dialect, dest interface{}, pk ...interface{}) error {
+	m := GetModelInfo(dest)
+	if m == nil {
+		log.Panicf("FindByPK argument must be a struct pointer, got %T", dest)
+	}
+	if len(m.PKFields) != len(pk) {
+		log.Panicf("FindByPK on %v expects %v pk value(s), got %v", m.Type, len(m.PKFields), len(pk))
+	}
+
+	wheres := make([]string, len(m.PKFields))
+	for i, field := range m.PKFields {
+		wheres[i] = fmt.Sprintf("%v = %v", field.Column, dialect.Placeholder(i+1))
+	}
+
+	query := fmt.Sprintf("SELECT %v FROM %v WHERE %v", m.FieldsSimple, m.TableName, strings.Join(wheres, " AND "))
+	return c.QueryRow(query, pk...).Scan(dest)
+}
+
+func _Upsert(c MConn, dialect Dialect, model interface{}, conflictCols ...string) error {
+	m := GetModelInfo(model)
+	if m == nil {
+		log.Panicf("Upsert argument must be a struct, got %T", model)
+	}
+
+	if len(conflictCols) == 0 {
+		for _, field := range m.PKFields {
+			conflictCols = append(conflictCols, field.Column)
+		}
+	}
+	if len(conflictCols) == 0 {
+		// No pk fields either: fall back to fields tagged "unique", which
+		// the database can also enforce a conflict target on.
+		for _, field := range m.Fields {
+			if field.Unique {
+				conflictCols = append(conflictCols, field.Column)
+			}
+		}
+	}
+	if len(conflictCols) == 0 {
+		log.Panicf(`Upsert on %v requires conflictCols, or fields tagged "pk" or "unique"`, m.Type)
+	}
+	isConflictCol := map[string]bool{}
+	for _, col := range conflictCols {
+		isConflictCol[col] = true
+	}
+
+	updateCols := []string{}
+	for _, field := range m.Fields {
+		if field.Autoinc || isConflictCol[field.Column] {
+			continue
+		}
+		updateCols = append(updateCols, field.Column)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %v (%v) VALUES (%v) %v",
+		m.TableName, m.FieldsInsert, m.Placeholders, dialect.UpsertClause(conflictCols, updateCols))
+	_, err := c.Exec(query, m.FieldValues(model)...)
+	return err
+}
+
+func setFieldInt(model interface{}, field *ModelField, value int64) {
+	v := reflect.ValueOf(model)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	v.FieldByName(field.Name).SetInt(value)
+}
+
+// isFieldZero reports whether model's field holds its type's zero value.
+func isFieldZero(model interface{}, field *ModelField) bool {
+	v := reflect.ValueOf(model)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	fieldValue := v.FieldByName(field.Name)
+	return fieldValue.Interface() == reflect.Zero(field.Type).Interface()
+}
+
+//-----------------------------------------------------------------------------
+// ModelDB
+
+func (mDB *ModelDB) Insert(model interface{}) error {
+	return _Insert(mDB, GetDialect(), model)
+}
+
+func (mDB *ModelDB) Update(model interface{}) error {
+	return _Update(mDB, GetDialect(), model)
+}
+
+func (mDB *ModelDB) Delete(model interface{}) error {
+	return _Delete(mDB, GetDialect(), model)
+}
+
+func (mDB *ModelDB) FindByPK(dest interface{}, pk ...interface{}) error {
+	return _FindByPK(mDB, GetDialect(), dest, pk...)
+}
+
+func (mDB *ModelDB) Upsert(model interface{}, conflictCols ...string) error {
+	return _Upsert(mDB, GetDialect(), model, conflictCols...)
+}
+
+//-----------------------------------------------------------------------------
+// ModelTx
+
+func (mTx *ModelTx) Insert(model interface{}) error {
+	return _Insert(mTx, GetDialect(), model)
+}
+
+func (mTx *ModelTx) Update(model interface{}) error {
+	return _Update(mTx, GetDialect(), model)
+}
+
+func (mTx *ModelTx) Delete(model interface{}) error {
+	return _Delete(mTx, GetDialect(), model)
+}
+
+func (mTx *ModelTx) FindByPK(dest interface{}, pk ...interface{}) error {
+	return _FindByPK(mTx, GetDialect(), dest, pk...)
+}
+
+func (mTx *ModelTx) Upsert(model interface{}, conflictCols ...string) error {
+	return _Upsert(mTx, GetDialect(), model, conflictCols...)
+}
+
+//-----------------------------------------------------------------------------
+// Convenience methods
+
+func Insert(model interface{}) error {
+	return _Insert(GetModelDB(), GetDialect(), model)
+}
+
+func Update(model interface{}) error {
+	return _Update(GetModelDB(), GetDialect(), model)
+}
+
+func Delete(model interface{}) error {
+	return _Delete(GetModelDB(), GetDialect(), model)
+}
+
+func FindByPK(dest interface{}, pk ...interface{}) error {
+	return _FindByPK(GetModelDB(), GetDialect(), dest, pk...)
+}
+
+func Upsert(model interface{}, conflictCols ...string) error {
+	return _Upsert(GetModelDB(), GetDialect(), model, conflictCols...)
+}
@@ -0,0 +1,130 @@
+package modeldb
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+// fakeResult is a minimal sql.Result for exercising _Insert's
+// LastInsertId fallback without a real driver.
+type fakeResult struct{ lastID int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return r.lastID, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+// fakeMConn records the last query/args passed to Exec. Query/QueryRow/
+// QueryAll aren't exercised by the tests below, which only cover code
+// paths that call Exec.
+type fakeMConn struct {
+	query string
+	args  []interface{}
+}
+
+func (c *fakeMConn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	c.query, c.args = query, args
+	return fakeResult{lastID: 7}, nil
+}
+
+func (c *fakeMConn) Query(query string, args ...interface{}) (*ModelRows, error) {
+	panic("not used in these tests")
+}
+
+func (c *fakeMConn) QueryRow(query string, args ...interface{}) *ModelRow {
+	panic("not used in these tests")
+}
+
+func (c *fakeMConn) QueryAll(proto interface{}, query string, args ...interface{}) (interface{}, error) {
+	panic("not used in these tests")
+}
+
+type crudInsertModel struct {
+	ID    int64  `db:"id,pk,autoinc"`
+	Name  string `db:"name"`
+	Note  string `db:"note,default='n/a'"`
+	Score int    `db:"score,default=0"`
+}
+
+func TestInsertOmitsZeroDefaultColumns(t *testing.T) {
+	c := &fakeMConn{}
+	model := &crudInsertModel{Name: "bob", Score: 42}
+
+	if err := _Insert(c, NewMySQLDialect(), model); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(c.query, "note") {
+		t.Errorf("query = %q, expected zero-valued default=-tagged column \"note\" to be omitted", c.query)
+	}
+	if !strings.Contains(c.query, "score") {
+		t.Errorf("query = %q, expected non-zero default=-tagged column \"score\" to be included", c.query)
+	}
+	if model.ID != 7 {
+		t.Errorf("ID = %v, want 7 (from LastInsertId)", model.ID)
+	}
+}
+
+type crudUpsertNoPKModel struct {
+	Email string `db:"email,unique"`
+	Name  string `db:"name"`
+}
+
+func TestUpsertFallsBackToUniqueField(t *testing.T) {
+	c := &fakeMConn{}
+	model := &crudUpsertNoPKModel{Email: "a@example.com", Name: "bob"}
+
+	if err := _Upsert(c, NewPostgresDialect(), model); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "ON CONFLICT (email)"; !strings.Contains(c.query, want) {
+		t.Errorf("query = %q, expected conflict target to fall back to the unique column: %q", c.query, want)
+	}
+}
+
+type crudUpsertJoinModel struct {
+	AID int64 `db:"a_id,pk"`
+	BID int64 `db:"b_id,pk"`
+}
+
+func TestUpsertEmptyUpdateColsEmitsDoNothing(t *testing.T) {
+	c := &fakeMConn{}
+	model := &crudUpsertJoinModel{AID: 1, BID: 2}
+
+	if err := _Upsert(c, NewPostgresDialect(), model); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "DO NOTHING"; !strings.Contains(c.query, want) {
+		t.Errorf("query = %q, want it to contain %q", c.query, want)
+	}
+}
+
+type crudUpdateModel struct {
+	ID   int64  `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+func TestUpdateSetsNonPKColumns(t *testing.T) {
+	c := &fakeMConn{}
+	model := &crudUpdateModel{ID: 1, Name: "bob"}
+
+	if err := _Update(c, NewPostgresDialect(), model); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "UPDATE crudupdatemodel SET name = $1 WHERE id = $2"; c.query != want {
+		t.Errorf("query = %q, want %q", c.query, want)
+	}
+	if len(c.args) != 2 || c.args[0] != "bob" || c.args[1] != int64(1) {
+		t.Errorf("args = %v, want [bob 1]", c.args)
+	}
+}
+
+func TestDeleteWhereByPK(t *testing.T) {
+	c := &fakeMConn{}
+	model := &crudUpdateModel{ID: 1, Name: "bob"}
+
+	if err := _Delete(c, NewPostgresDialect(), model); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "DELETE FROM crudupdatemodel WHERE id = $1"; c.query != want {
+		t.Errorf("query = %q, want %q", c.query, want)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+This file defines the Dialect abstraction that lets the rest of the
+package stay driver-agnostic. A Dialect knows how to render bind
+placeholders and how to classify a driver's opaque errors into the
+package's ERR_* sentinels. See dialect_postgres.go, dialect_mysql.go,
+and dialect_sqlite3.go for the concrete implementations.
+*/
+
+package modeldb
+
+// Dialect abstracts the parts of SQL generation and error handling that
+// differ between database drivers.
+type Dialect interface {
+	// Name returns the dialect's short name, e.g. "postgres".
+	Name() string
+
+	// Placeholder returns the bind placeholder for the i'th (1-indexed)
+	// argument in a query, e.g. "$1" for postgres or "?" for mysql/sqlite3.
+	Placeholder(i int) string
+
+	// GetErrorType classifies a driver error into one of the ERR_*
+	// sentinels declared in error.go.
+	GetErrorType(err error) error
+
+	// GetErrorMessage extracts the human-readable message from a driver error.
+	GetErrorMessage(err error) string
+
+	// GetDuplicateKey extracts the offending constraint/key name from a
+	// duplicate-key error.
+	GetDuplicateKey(err error) string
+
+	// SupportsReturning reports whether INSERT ... RETURNING is
+	// available, so Insert can read back the autoinc column directly
+	// instead of falling back to sql.Result.LastInsertId.
+	SupportsReturning() bool
+
+	// ReturningClause returns the " RETURNING col" suffix for an INSERT
+	// statement, or "" if SupportsReturning is false.
+	ReturningClause(col string) string
+
+	// UpsertClause returns the ON CONFLICT/ON DUPLICATE KEY suffix
+	// appended after an INSERT's VALUES(...), given the conflict columns
+	// and the columns to update when a row already exists.
+	UpsertClause(conflictCols, updateCols []string) string
+}
+
+// The dialect used by Exec/Query/etc when none is given explicitly.
+// Defaults to postgres to match this package's original behavior.
+var _dialect Dialect = NewPostgresDialect()
+
+// SetDialect sets the dialect used for placeholder rendering and error
+// classification. Call this once at startup if you're not on postgres.
+func SetDialect(dialect Dialect) {
+	_dialect = dialect
+}
+
+// GetDialect returns the currently configured dialect.
+func GetDialect() Dialect {
+	return _dialect
+}
@@ -0,0 +1,88 @@
+/*
+This file is the mysql Dialect, using github.com/go-sql-driver/mysql.
+*/
+
+package modeldb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+var reMysqlDuplicateKey = regexp.MustCompile("Duplicate entry.*for key '(.+)'")
+
+type MySQLDialect struct{}
+
+func NewMySQLDialect() *MySQLDialect {
+	return &MySQLDialect{}
+}
+
+func (MySQLDialect) Name() string { return "mysql" }
+
+func (MySQLDialect) Placeholder(i int) string {
+	return "?"
+}
+
+// http://dev.mysql.com/doc/refman/5.5/en/error-messages-server.html#error_er_dup_entry
+func (MySQLDialect) GetErrorType(err error) error {
+	if err == nil {
+		return nil
+	}
+	dbErr, ok := err.(*mysql.MySQLError)
+	if !ok {
+		return err
+	}
+	switch dbErr.Number {
+	case 1062:
+		return ERR_DUPLICATE_ENTRY
+	case 1213:
+		return ERR_SERIAL_TX
+	default:
+		return ERR_OTHER
+	}
+}
+
+func (MySQLDialect) GetErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	dbErr, ok := err.(*mysql.MySQLError)
+	if ok {
+		return dbErr.Message
+	}
+	panic("Message unknown for err")
+}
+
+func (MySQLDialect) GetDuplicateKey(err error) string {
+	dbErr, ok := err.(*mysql.MySQLError)
+	if ok {
+		match := reMysqlDuplicateKey.FindStringSubmatch(dbErr.Message)
+		if match == nil {
+			panic("Not a duplicate key error")
+		}
+		return match[1]
+	}
+	panic("Message unknown for err")
+}
+
+func (MySQLDialect) SupportsReturning() bool { return false }
+
+func (MySQLDialect) ReturningClause(col string) string { return "" }
+
+// mysql has no "do nothing on conflict" clause, so when there are no
+// non-conflict columns to update we fall back to a no-op self-assignment
+// on the first conflict column, to keep the UPDATE clause non-empty.
+func (MySQLDialect) UpsertClause(conflictCols, updateCols []string) string {
+	if len(updateCols) == 0 {
+		col := conflictCols[0]
+		return fmt.Sprintf("ON DUPLICATE KEY UPDATE %v = %v", col, col)
+	}
+	sets := make([]string, len(updateCols))
+	for i, col := range updateCols {
+		sets[i] = fmt.Sprintf("%v = VALUES(%v)", col, col)
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
@@ -0,0 +1,83 @@
+/*
+This file is the postgres Dialect, using github.com/lib/pq.
+*/
+
+package modeldb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type PostgresDialect struct{}
+
+func NewPostgresDialect() *PostgresDialect {
+	return &PostgresDialect{}
+}
+
+func (PostgresDialect) Name() string { return "postgres" }
+
+func (PostgresDialect) Placeholder(i int) string {
+	return fmt.Sprintf("$%v", i)
+}
+
+// https://github.com/lib/pq/blob/master/error.go
+func (PostgresDialect) GetErrorType(err error) error {
+	if err == nil {
+		return nil
+	}
+	dbErr, ok := err.(*pq.Error)
+	if !ok {
+		return err
+	}
+	switch dbErr.Code {
+	case "23505":
+		return ERR_DUPLICATE_ENTRY
+	case "40001":
+		return ERR_SERIAL_TX
+	default:
+		return ERR_OTHER
+	}
+}
+
+func (PostgresDialect) GetErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	dbErr, ok := err.(*pq.Error)
+	if ok {
+		return dbErr.Message
+	}
+	panic("Message unknown for err")
+}
+
+func (PostgresDialect) GetDuplicateKey(err error) string {
+	dbErr, ok := err.(*pq.Error)
+	if ok {
+		return dbErr.Constraint
+	}
+	panic("Message unknown for err")
+}
+
+func (PostgresDialect) SupportsReturning() bool { return true }
+
+func (PostgresDialect) ReturningClause(col string) string {
+	if col == "" {
+		return ""
+	}
+	return " RETURNING " + col
+}
+
+func (PostgresDialect) UpsertClause(conflictCols, updateCols []string) string {
+	if len(updateCols) == 0 {
+		return fmt.Sprintf("ON CONFLICT (%v) DO NOTHING", strings.Join(conflictCols, ", "))
+	}
+	sets := make([]string, len(updateCols))
+	for i, col := range updateCols {
+		sets[i] = fmt.Sprintf("%v = EXCLUDED.%v", col, col)
+	}
+	return fmt.Sprintf("ON CONFLICT (%v) DO UPDATE SET %v",
+		strings.Join(conflictCols, ", "), strings.Join(sets, ", "))
+}
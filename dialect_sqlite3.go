@@ -0,0 +1,84 @@
+/*
+This file is the sqlite3 Dialect, using github.com/mattn/go-sqlite3.
+*/
+
+package modeldb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+type SQLite3Dialect struct{}
+
+func NewSQLite3Dialect() *SQLite3Dialect {
+	return &SQLite3Dialect{}
+}
+
+func (SQLite3Dialect) Name() string { return "sqlite3" }
+
+func (SQLite3Dialect) Placeholder(i int) string {
+	return "?"
+}
+
+// https://github.com/mattn/go-sqlite3/blob/master/error.go
+func (SQLite3Dialect) GetErrorType(err error) error {
+	if err == nil {
+		return nil
+	}
+	dbErr, ok := err.(sqlite3.Error)
+	if !ok {
+		return err
+	}
+	switch dbErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return ERR_DUPLICATE_ENTRY
+	}
+	// ErrBusy/ErrLocked are base ErrNo codes, not ErrNoExtended, so they're
+	// checked against dbErr.Code rather than folded into the switch above.
+	switch dbErr.Code {
+	case sqlite3.ErrBusy, sqlite3.ErrLocked:
+		return ERR_SERIAL_TX
+	}
+	return ERR_OTHER
+}
+
+func (SQLite3Dialect) GetErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	dbErr, ok := err.(sqlite3.Error)
+	if ok {
+		return dbErr.Error()
+	}
+	panic("Message unknown for err")
+}
+
+func (SQLite3Dialect) GetDuplicateKey(err error) string {
+	// sqlite3 doesn't report the constraint name on the error, only that
+	// a uniqueness constraint was violated.
+	_, ok := err.(sqlite3.Error)
+	if ok {
+		return ""
+	}
+	panic("Message unknown for err")
+}
+
+func (SQLite3Dialect) SupportsReturning() bool { return false }
+
+func (SQLite3Dialect) ReturningClause(col string) string { return "" }
+
+// sqlite3 has supported the postgres-style upsert syntax since 3.24.0.
+func (SQLite3Dialect) UpsertClause(conflictCols, updateCols []string) string {
+	if len(updateCols) == 0 {
+		return fmt.Sprintf("ON CONFLICT (%v) DO NOTHING", strings.Join(conflictCols, ", "))
+	}
+	sets := make([]string, len(updateCols))
+	for i, col := range updateCols {
+		sets[i] = fmt.Sprintf("%v = EXCLUDED.%v", col, col)
+	}
+	return fmt.Sprintf("ON CONFLICT (%v) DO UPDATE SET %v",
+		strings.Join(conflictCols, ", "), strings.Join(sets, ", "))
+}
@@ -0,0 +1,112 @@
+package modeldb
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+func TestPostgresDialectGetErrorType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"duplicate", &pq.Error{Code: "23505"}, ERR_DUPLICATE_ENTRY},
+		{"serialization failure", &pq.Error{Code: "40001"}, ERR_SERIAL_TX},
+		{"other code", &pq.Error{Code: "42601"}, ERR_OTHER},
+	}
+	d := NewPostgresDialect()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.GetErrorType(tt.err); got != tt.want {
+				t.Errorf("GetErrorType(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDialectGetErrorType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"duplicate", &mysql.MySQLError{Number: 1062}, ERR_DUPLICATE_ENTRY},
+		{"deadlock", &mysql.MySQLError{Number: 1213}, ERR_SERIAL_TX},
+		{"other code", &mysql.MySQLError{Number: 1146}, ERR_OTHER},
+	}
+	d := NewMySQLDialect()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.GetErrorType(tt.err); got != tt.want {
+				t.Errorf("GetErrorType(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLite3DialectGetErrorType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"unique constraint", sqlite3.Error{ExtendedCode: sqlite3.ErrConstraintUnique}, ERR_DUPLICATE_ENTRY},
+		{"pk constraint", sqlite3.Error{ExtendedCode: sqlite3.ErrConstraintPrimaryKey}, ERR_DUPLICATE_ENTRY},
+		{"busy", sqlite3.Error{Code: sqlite3.ErrBusy}, ERR_SERIAL_TX},
+		{"locked", sqlite3.Error{Code: sqlite3.ErrLocked}, ERR_SERIAL_TX},
+		{"other", sqlite3.Error{Code: sqlite3.ErrError}, ERR_OTHER},
+	}
+	d := NewSQLite3Dialect()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.GetErrorType(tt.err); got != tt.want {
+				t.Errorf("GetErrorType(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertClauseEmptyUpdateCols(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		want    string
+	}{
+		{"postgres", NewPostgresDialect(), "ON CONFLICT (id) DO NOTHING"},
+		{"sqlite3", NewSQLite3Dialect(), "ON CONFLICT (id) DO NOTHING"},
+		{"mysql", NewMySQLDialect(), "ON DUPLICATE KEY UPDATE id = id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.UpsertClause([]string{"id"}, nil); got != tt.want {
+				t.Errorf("UpsertClause(%v) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertClauseWithUpdateCols(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		want    string
+	}{
+		{"postgres", NewPostgresDialect(), "ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name"},
+		{"sqlite3", NewSQLite3Dialect(), "ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name"},
+		{"mysql", NewMySQLDialect(), "ON DUPLICATE KEY UPDATE name = VALUES(name)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.UpsertClause([]string{"id"}, []string{"name"}); got != tt.want {
+				t.Errorf("UpsertClause(%v) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
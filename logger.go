@@ -0,0 +1,78 @@
+/*
+This file gives visibility into the SQL this package executes: a
+pluggable Logger (with a default that writes to the standard "log"
+package and escalates slow queries), plus BeforeQuery/AfterQuery hook
+slots for wiring in something like OpenTelemetry spans or Prometheus
+histograms.
+*/
+
+package modeldb
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Logger receives a record of every query _Exec/_Query/_QueryRow/
+// _QueryAll (and their *Context variants) run.
+type Logger interface {
+	LogQuery(ctx context.Context, query string, args []interface{}, duration time.Duration, err error)
+}
+
+// LoggerFunc lets a plain function satisfy Logger.
+type LoggerFunc func(ctx context.Context, query string, args []interface{}, duration time.Duration, err error)
+
+func (f LoggerFunc) LogQuery(ctx context.Context, query string, args []interface{}, duration time.Duration, err error) {
+	f(ctx, query, args, duration, err)
+}
+
+var _logger Logger = defaultLogger{}
+
+// SetLogger replaces the package's Logger. Defaults to one that writes
+// to the standard "log" package.
+func SetLogger(l Logger) {
+	_logger = l
+}
+
+// SlowThreshold escalates queries taking at least this long to a
+// "SLOW QUERY" log line under the default Logger. Zero (the default)
+// disables escalation. Custom Loggers can read this too.
+var SlowThreshold time.Duration
+
+// BeforeQuery, if set, is called before every query runs.
+var BeforeQuery func(ctx context.Context, query string, args []interface{})
+
+// AfterQuery, if set, is called after every query runs, in addition to
+// the configured Logger. Use this for metrics/tracing hooks that want
+// the raw (ctx, query, args, duration, err) without implementing Logger.
+var AfterQuery func(ctx context.Context, query string, args []interface{}, duration time.Duration, err error)
+
+type defaultLogger struct{}
+
+func (defaultLogger) LogQuery(ctx context.Context, query string, args []interface{}, duration time.Duration, err error) {
+	switch {
+	case err != nil:
+		log.Printf("modeldb: query error after %v: %v -- %v %v", duration, err, query, args)
+	case SlowThreshold > 0 && duration >= SlowThreshold:
+		log.Printf("modeldb: SLOW QUERY (%v): %v %v", duration, query, args)
+	default:
+		log.Printf("modeldb: (%v) %v %v", duration, query, args)
+	}
+}
+
+func beforeQuery(ctx context.Context, query string, args []interface{}) {
+	if BeforeQuery != nil {
+		BeforeQuery(ctx, query, args)
+	}
+}
+
+// logQuery runs the AfterQuery hook (if set) and the configured Logger
+// for a query that started at start. BeforeQuery is run by beforeQuery.
+func logQuery(ctx context.Context, query string, args []interface{}, start time.Time, err error) {
+	duration := time.Since(start)
+	if AfterQuery != nil {
+		AfterQuery(ctx, query, args, duration, err)
+	}
+	_logger.LogQuery(ctx, query, args, duration, err)
+}
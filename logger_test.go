@@ -0,0 +1,84 @@
+package modeldb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerFuncSatisfiesLogger(t *testing.T) {
+	var gotErr error
+	f := LoggerFunc(func(ctx context.Context, query string, args []interface{}, duration time.Duration, err error) {
+		gotErr = err
+	})
+	var l Logger = f
+	l.LogQuery(context.Background(), "SELECT 1", nil, time.Millisecond, errors.New("boom"))
+	if gotErr == nil || gotErr.Error() != "boom" {
+		t.Errorf("err = %v, want boom", gotErr)
+	}
+}
+
+func TestBeforeQueryAfterQueryHooks(t *testing.T) {
+	origLogger, origBefore, origAfter := _logger, BeforeQuery, AfterQuery
+	t.Cleanup(func() { _logger, BeforeQuery, AfterQuery = origLogger, origBefore, origAfter })
+
+	var beforeCalled, afterCalled, loggerCalled bool
+	BeforeQuery = func(ctx context.Context, query string, args []interface{}) { beforeCalled = true }
+	AfterQuery = func(ctx context.Context, query string, args []interface{}, d time.Duration, err error) {
+		afterCalled = true
+	}
+	SetLogger(LoggerFunc(func(ctx context.Context, query string, args []interface{}, d time.Duration, err error) {
+		loggerCalled = true
+	}))
+
+	beforeQuery(context.Background(), "SELECT 1", nil)
+	logQuery(context.Background(), "SELECT 1", nil, time.Now(), nil)
+
+	if !beforeCalled || !afterCalled || !loggerCalled {
+		t.Errorf("beforeCalled=%v afterCalled=%v loggerCalled=%v, want all true", beforeCalled, afterCalled, loggerCalled)
+	}
+}
+
+func TestBeforeQueryNoopWhenUnset(t *testing.T) {
+	origBefore := BeforeQuery
+	BeforeQuery = nil
+	t.Cleanup(func() { BeforeQuery = origBefore })
+
+	// Must not panic when no hook is installed.
+	beforeQuery(context.Background(), "SELECT 1", nil)
+}
+
+func TestDefaultLoggerSlowThresholdEscalation(t *testing.T) {
+	origOut, origFlags := log.Writer(), log.Flags()
+	log.SetFlags(0)
+	t.Cleanup(func() { log.SetOutput(origOut); log.SetFlags(origFlags) })
+
+	origSlow := SlowThreshold
+	t.Cleanup(func() { SlowThreshold = origSlow })
+	SlowThreshold = 10 * time.Millisecond
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	var l defaultLogger
+
+	l.LogQuery(context.Background(), "SELECT 1", nil, 20*time.Millisecond, nil)
+	if !strings.Contains(buf.String(), "SLOW QUERY") {
+		t.Errorf("log output = %q, want it to escalate to SLOW QUERY above SlowThreshold", buf.String())
+	}
+
+	buf.Reset()
+	l.LogQuery(context.Background(), "SELECT 1", nil, time.Millisecond, nil)
+	if strings.Contains(buf.String(), "SLOW QUERY") {
+		t.Errorf("log output = %q, should not escalate below SlowThreshold", buf.String())
+	}
+
+	buf.Reset()
+	l.LogQuery(context.Background(), "SELECT 1", nil, time.Millisecond, errors.New("boom"))
+	if !strings.Contains(buf.String(), "query error") {
+		t.Errorf("log output = %q, want it to report the query error", buf.String())
+	}
+}
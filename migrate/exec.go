@@ -0,0 +1,51 @@
+package migrate
+
+import (
+	"strings"
+
+	"github.com/jaekwon/go-modeldb"
+)
+
+// execStatements runs sqlText against tx one statement at a time, split on
+// ';', except inside "-- +migrate StatementBegin" / "StatementEnd" blocks
+// (e.g. a PL/pgSQL function body containing its own semicolons), which run
+// as a single statement.
+func execStatements(tx *modeldb.ModelTx, sqlText string) error {
+	var stmt strings.Builder
+	inBlock := false
+
+	flush := func() error {
+		s := strings.TrimSpace(stmt.String())
+		stmt.Reset()
+		if s == "" {
+			return nil
+		}
+		_, err := tx.Exec(s)
+		return err
+	}
+
+	for _, line := range strings.Split(sqlText, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "-- +migrate StatementBegin"):
+			inBlock = true
+			continue
+		case strings.HasPrefix(trimmed, "-- +migrate StatementEnd"):
+			inBlock = false
+			if err := flush(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		stmt.WriteString(line)
+		stmt.WriteString("\n")
+
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
@@ -0,0 +1,227 @@
+/*
+Package migrate is a small schema-migration runner modeled after goose:
+migrations live as ordered, numbered files (SQL or Go) in a directory (or
+an embed.FS for production binaries), applied versions are tracked in a
+schema_migrations table, and Up/Down/UpTo/Redo/GetStatus drive them against
+the package-global DB set via modeldb.SetDB.
+*/
+
+package migrate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jaekwon/go-modeldb"
+)
+
+// A single migration, identified by its version number. Exactly one of
+// (UpSQL, DownSQL) or (UpFunc, DownFunc) is populated, depending on
+// whether it was loaded from a .sql file or registered with AddMigration.
+type Migration struct {
+	Version int64
+	Name    string
+
+	UpSQL   string
+	DownSQL string
+
+	UpFunc   func(*modeldb.ModelTx) error
+	DownFunc func(*modeldb.ModelTx) error
+}
+
+func (m *Migration) runUp(tx *modeldb.ModelTx) error {
+	if m.UpFunc != nil {
+		return m.UpFunc(tx)
+	}
+	return execStatements(tx, m.UpSQL)
+}
+
+func (m *Migration) runDown(tx *modeldb.ModelTx) error {
+	if m.DownFunc != nil {
+		return m.DownFunc(tx)
+	}
+	return execStatements(tx, m.DownSQL)
+}
+
+// migrations registered in-process via AddMigration, e.g. from an init()
+// in a Go migration file.
+var registered = map[int64]*Migration{}
+
+// AddMigration registers a Go-func migration. Call this from an init()
+// in a file alongside your .sql migrations; it takes part in the same
+// version ordering.
+func AddMigration(version int64, name string, up, down func(*modeldb.ModelTx) error) {
+	registered[version] = &Migration{
+		Version:  version,
+		Name:     name,
+		UpFunc:   up,
+		DownFunc: down,
+	}
+}
+
+const createSchemaMigrationsSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT NOT NULL PRIMARY KEY,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+func ensureSchemaMigrationsTable() error {
+	_, err := modeldb.Exec(createSchemaMigrationsSQL)
+	return err
+}
+
+// AppliedVersions returns the versions already recorded in
+// schema_migrations, in ascending order.
+func AppliedVersions() ([]int64, error) {
+	if err := ensureSchemaMigrationsTable(); err != nil {
+		return nil, err
+	}
+	rows, err := modeldb.Query(`SELECT version FROM schema_migrations ORDER BY version ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	versions := []int64{}
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// Status describes one migration's applied state.
+type Status struct {
+	Version int64
+	Name    string
+	Applied bool
+}
+
+// GetStatus reports the applied state of every migration in migrations,
+// sorted by version.
+func GetStatus(migrations []*Migration) ([]Status, error) {
+	applied, err := AppliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	appliedSet := map[int64]bool{}
+	for _, v := range applied {
+		appliedSet[v] = true
+	}
+
+	sorted := sortedMigrations(migrations)
+	statuses := make([]Status, len(sorted))
+	for i, m := range sorted {
+		statuses[i] = Status{Version: m.Version, Name: m.Name, Applied: appliedSet[m.Version]}
+	}
+	return statuses, nil
+}
+
+func sortedMigrations(migrations []*Migration) []*Migration {
+	sorted := append([]*Migration{}, migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return sorted
+}
+
+// Up applies every migration in migrations that hasn't been applied yet,
+// in ascending version order.
+func Up(migrations []*Migration) error {
+	return UpTo(migrations, 0)
+}
+
+// UpTo applies migrations up to and including version. A version of 0
+// means "no limit" (apply everything pending).
+func UpTo(migrations []*Migration, version int64) error {
+	applied, err := AppliedVersions()
+	if err != nil {
+		return err
+	}
+	appliedSet := map[int64]bool{}
+	for _, v := range applied {
+		appliedSet[v] = true
+	}
+
+	for _, m := range sortedMigrations(migrations) {
+		if appliedSet[m.Version] {
+			continue
+		}
+		if version != 0 && m.Version > version {
+			break
+		}
+		if err := applyUp(m); err != nil {
+			return fmt.Errorf("migrate: applying %v_%v: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down rolls back the single most recently applied migration.
+func Down(migrations []*Migration) error {
+	applied, err := AppliedVersions()
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+	last := applied[len(applied)-1]
+
+	byVersion := map[int64]*Migration{}
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+	m, ok := byVersion[last]
+	if !ok {
+		return fmt.Errorf("migrate: applied version %v has no corresponding migration", last)
+	}
+	return applyDown(m)
+}
+
+// Redo rolls back and reapplies the most recently applied migration.
+func Redo(migrations []*Migration) error {
+	applied, err := AppliedVersions()
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+	last := applied[len(applied)-1]
+
+	byVersion := map[int64]*Migration{}
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+	m, ok := byVersion[last]
+	if !ok {
+		return fmt.Errorf("migrate: applied version %v has no corresponding migration", last)
+	}
+	if err := applyDown(m); err != nil {
+		return err
+	}
+	return applyUp(m)
+}
+
+func applyUp(m *Migration) error {
+	return modeldb.DoBegin("", func(tx *modeldb.ModelTx) {
+		if err := m.runUp(tx); err != nil {
+			panic(err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, m.Version); err != nil {
+			panic(err)
+		}
+	})
+}
+
+func applyDown(m *Migration) error {
+	return modeldb.DoBegin("", func(tx *modeldb.ModelTx) {
+		if err := m.runDown(tx); err != nil {
+			panic(err)
+		}
+		if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, m.Version); err != nil {
+			panic(err)
+		}
+	})
+}
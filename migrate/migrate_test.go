@@ -0,0 +1,114 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jaekwon/go-modeldb"
+)
+
+func TestParseSQLMigration(t *testing.T) {
+	data := `
+-- +migrate Up
+CREATE TABLE foo (id INT);
+
+-- +migrate Down
+DROP TABLE foo;
+`
+	up, down, err := parseSQLMigration(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "CREATE TABLE foo (id INT);\n"; up != want {
+		t.Errorf("up = %q, want %q", up, want)
+	}
+	if want := "DROP TABLE foo;\n"; down != want {
+		t.Errorf("down = %q, want %q", down, want)
+	}
+}
+
+func TestParseSQLMigrationStatementBlock(t *testing.T) {
+	data := `
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE FUNCTION f() RETURNS void AS $$
+BEGIN
+  PERFORM 1;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP FUNCTION f();
+`
+	up, down, err := parseSQLMigration(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The StatementBegin/StatementEnd markers and the semicolons inside the
+	// block are both preserved, for execStatements to treat the block as a
+	// single statement.
+	if !strings.Contains(up, "BEGIN") || !strings.Contains(up, "END;") {
+		t.Errorf("up section missing function body: %q", up)
+	}
+	if !strings.Contains(up, "StatementBegin") || !strings.Contains(up, "StatementEnd") {
+		t.Errorf("up section should contain the marker comments: %q", up)
+	}
+	if want := "DROP FUNCTION f();\n"; down != want {
+		t.Errorf("down = %q, want %q", down, want)
+	}
+}
+
+func TestParseSQLMigrationIgnoresContentBeforeUp(t *testing.T) {
+	data := "-- some header comment\n-- +migrate Up\nSELECT 1;\n"
+	up, _, err := parseSQLMigration(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "SELECT 1;\n"; up != want {
+		t.Errorf("up = %q, want %q", up, want)
+	}
+}
+
+func TestCollectMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/00002_add_bar.sql": &fstest.MapFile{
+			Data: []byte("-- +migrate Up\nCREATE TABLE bar (id INT);\n-- +migrate Down\nDROP TABLE bar;\n"),
+		},
+		"migrations/00001_add_foo.sql": &fstest.MapFile{
+			Data: []byte("-- +migrate Up\nCREATE TABLE foo (id INT);\n-- +migrate Down\nDROP TABLE foo;\n"),
+		},
+		"migrations/not_a_migration.txt": &fstest.MapFile{
+			Data: []byte("ignored"),
+		},
+	}
+
+	migrations, err := CollectMigrations(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("got %v migrations, want 2", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "add_foo" {
+		t.Errorf("migrations[0] = %+v, want version 1 add_foo", migrations[0])
+	}
+	if migrations[1].Version != 2 || migrations[1].Name != "add_bar" {
+		t.Errorf("migrations[1] = %+v, want version 2 add_bar", migrations[1])
+	}
+}
+
+func TestCollectMigrationsDuplicateVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/00001_a.sql": &fstest.MapFile{Data: []byte("-- +migrate Up\nSELECT 1;\n")},
+	}
+	noop := func(*modeldb.ModelTx) error { return nil }
+	AddMigration(1, "registered", noop, noop)
+	t.Cleanup(func() { delete(registered, 1) })
+
+	_, err := CollectMigrations(fsys, "migrations")
+	if err == nil {
+		t.Fatal("expected a duplicate-version error, got nil")
+	}
+}
@@ -0,0 +1,131 @@
+package migrate
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var reVersionedFile = regexp.MustCompile(`^(\d+)_(.*)\.sql$`)
+
+// CollectMigrations reads every *.sql file directly under dir in fsys
+// (e.g. an embed.FS, or os.DirFS(".") for a plain directory), parses its
+// "-- +migrate Up"/"-- +migrate Down" sections, and merges in any
+// migrations registered via AddMigration. Files must be named
+// "<version>_<name>.sql", e.g. "00001_create_users.sql".
+func CollectMigrations(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for version, m := range registered {
+		byVersion[version] = m
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := reVersionedFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: bad version in filename %v: %w", entry.Name(), err)
+		}
+		if _, exists := byVersion[version]; exists {
+			return nil, fmt.Errorf("migrate: duplicate migration version %v", version)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		upSQL, downSQL, err := parseSQLMigration(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: parsing %v: %w", entry.Name(), err)
+		}
+
+		byVersion[version] = &Migration{
+			Version: version,
+			Name:    match[2],
+			UpSQL:   upSQL,
+			DownSQL: downSQL,
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseSQLMigration splits a goose-style migration file into its Up and
+// Down sections, honoring "-- +migrate StatementBegin"/"StatementEnd"
+// around multi-statement blocks (e.g. PL/pgSQL functions) that would
+// otherwise be split on ';'.
+func parseSQLMigration(data string) (upSQL, downSQL string, err error) {
+	const (
+		sectionNone = iota
+		sectionUp
+		sectionDown
+	)
+
+	section := sectionNone
+	var upLines, downLines []string
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "-- +migrate Up"):
+			section = sectionUp
+			continue
+		case strings.HasPrefix(trimmed, "-- +migrate Down"):
+			section = sectionDown
+			continue
+		}
+		// "-- +migrate StatementBegin"/"StatementEnd" markers are kept in
+		// the returned SQL: execStatements reads them to run the block
+		// between them as a single statement instead of splitting on ';'.
+
+		switch section {
+		case sectionUp:
+			upLines = append(upLines, line)
+		case sectionDown:
+			downLines = append(downLines, line)
+		case sectionNone:
+			// Content before the first "-- +migrate Up" marker is ignored,
+			// same as goose.
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+	return joinSQLLines(upLines), joinSQLLines(downLines), nil
+}
+
+// joinSQLLines joins lines into a single SQL string, trimming blank lines
+// left trailing at a section boundary (e.g. a blank line separating the
+// last statement from the next "-- +migrate" marker).
+func joinSQLLines(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -1,13 +1,14 @@
 package modeldb
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"log"
 	"reflect"
 	"runtime"
 	"strings"
+	"time"
 )
 
 //-----------------------------------------------------------------------------
@@ -16,7 +17,8 @@ import (
 // A global db instance, for convenience
 var _db *sql.DB
 
-// Set one here once per app
+// Set one here once per app. Defaults to the postgres Dialect; call
+// SetDialect beforehand if you're on mysql or sqlite3.
 func SetDB(db *sql.DB) {
 	_db = db
 }
@@ -62,6 +64,9 @@ type ModelField struct {
 	Column  string
 	Null    bool
 	Autoinc bool
+	PK      bool
+	Unique  bool
+	Default string
 }
 
 // Represents meta info about a model
@@ -73,6 +78,13 @@ type ModelInfo struct {
 	FieldsPrefixed string
 	FieldsInsert   string
 	Placeholders   string
+
+	// PKFields are the fields tagged "pk", in declaration order. Most
+	// models have exactly one; composite keys are supported.
+	PKFields []*ModelField
+
+	// AutoincField is the field tagged "autoinc", or nil if none.
+	AutoincField *ModelField
 }
 
 // Global cache
@@ -113,8 +125,15 @@ func GetModelInfoFromType(modelType reflect.Type) *ModelInfo {
 	for i := 0; i < numFields; i++ {
 		field := m.Type.Field(i)
 		if field.Tag.Get("db") != "" {
-			column, null, autoinc := parseDBTag(field.Tag.Get("db"))
-			m.Fields = append(m.Fields, &ModelField{field, column, null, autoinc})
+			tag := parseDBTag(field.Tag.Get("db"))
+			mField := &ModelField{field, tag.column, tag.null, tag.autoinc, tag.pk, tag.unique, tag.def}
+			m.Fields = append(m.Fields, mField)
+			if mField.PK {
+				m.PKFields = append(m.PKFields, mField)
+			}
+			if mField.Autoinc {
+				m.AutoincField = mField
+			}
 		}
 	}
 
@@ -123,11 +142,10 @@ func GetModelInfoFromType(modelType reflect.Type) *ModelInfo {
 	fieldInsertNames := []string{}
 	ph := []string{}
 	for _, field := range m.Fields {
-		fieldName, _, _ := parseDBTag(field.Tag.Get("db"))
-		fieldNames = append(fieldNames, fieldName)
+		fieldNames = append(fieldNames, field.Column)
 		if !field.Autoinc {
-			fieldInsertNames = append(fieldInsertNames, fieldName)
-			ph = append(ph, fmt.Sprintf("$%v", len(ph)+1))
+			fieldInsertNames = append(fieldInsertNames, field.Column)
+			ph = append(ph, GetDialect().Placeholder(len(ph)+1))
 		}
 	}
 
@@ -139,18 +157,34 @@ func GetModelInfoFromType(modelType reflect.Type) *ModelInfo {
 	return m
 }
 
-func parseDBTag(tag string) (fieldName string, null bool, autoinc bool) {
+// dbTag holds the parsed form of a `db:"col,null,pk,unique,autoinc,default=..."` tag.
+type dbTag struct {
+	column  string
+	null    bool
+	autoinc bool
+	pk      bool
+	unique  bool
+	def     string
+}
+
+func parseDBTag(tag string) dbTag {
 	s := strings.Split(tag, ",")
-	fieldName = s[0]
+	t := dbTag{column: s[0]}
 	for _, ss := range s[1:] {
-		if ss == "null" {
-			null = true
-		}
-		if ss == "autoinc" {
-			autoinc = true
+		switch {
+		case ss == "null":
+			t.null = true
+		case ss == "autoinc":
+			t.autoinc = true
+		case ss == "pk":
+			t.pk = true
+		case ss == "unique":
+			t.unique = true
+		case strings.HasPrefix(ss, "default="):
+			t.def = strings.TrimPrefix(ss, "default=")
 		}
 	}
-	return
+	return t
 }
 
 // Split a struct value into field values, for insertion
@@ -179,6 +213,20 @@ func (m *ModelInfo) FieldValues(i interface{}) []interface{} {
 	return fvs
 }
 
+// FieldValue returns i's value for a single field, the same way
+// FieldValues does (nil for a zero-valued nullable field).
+func (m *ModelInfo) FieldValue(i interface{}, field *ModelField) interface{} {
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	fieldValue := v.FieldByName(field.Name)
+	if field.Null && fieldValue.Interface() == reflect.Zero(field.Type).Interface() {
+		return nil
+	}
+	return fieldValue.Interface()
+}
+
 //-----------------------------------------------------------------------------
 // Common methods
 
@@ -213,20 +261,7 @@ func scanStruct(scanner RowScanner, dest ...interface{}) error {
 			m := GetModelInfoFromType(dValue.Type())
 			for _, field := range m.Fields {
 				dField := dValue.FieldByName(field.Name)
-				if field.Null {
-					switch field.Type.Name() {
-					case "string":
-						ns := NullString(dField.Interface().(string))
-						destValuesP = append(destValuesP, &ns)
-					case "int64":
-						ni := NullInt64(dField.Interface().(int64))
-						destValuesP = append(destValuesP, &ni)
-					default:
-						panic(errors.New("Dunno how to convert nil to " + field.Type.Name()))
-					}
-				} else {
-					destValuesP = append(destValuesP, dField.Addr().Interface())
-				}
+				destValuesP = append(destValuesP, fieldScanDest(field, dField))
 			}
 		}
 	}
@@ -234,15 +269,29 @@ func scanStruct(scanner RowScanner, dest ...interface{}) error {
 }
 
 func _Exec(c Conn, query string, args ...interface{}) (sql.Result, error) {
-	return c.Exec(ConvertPH(query), expandArgs(args...)...)
+	q, a := ConvertPH(query, GetDialect()), expandArgs(args...)
+	beforeQuery(context.Background(), q, a)
+	start := time.Now()
+	res, err := c.Exec(q, a...)
+	logQuery(context.Background(), q, a, start, err)
+	return res, err
 }
 
 func _QueryRow(c Conn, query string, args ...interface{}) *ModelRow {
-	return &ModelRow{c.QueryRow(ConvertPH(query), expandArgs(args...)...)}
+	q, a := ConvertPH(query, GetDialect()), expandArgs(args...)
+	beforeQuery(context.Background(), q, a)
+	start := time.Now()
+	row := c.QueryRow(q, a...)
+	logQuery(context.Background(), q, a, start, nil)
+	return &ModelRow{row}
 }
 
 func _Query(c Conn, query string, args ...interface{}) (*ModelRows, error) {
-	rows, err := c.Query(ConvertPH(query), expandArgs(args...)...)
+	q, a := ConvertPH(query, GetDialect()), expandArgs(args...)
+	beforeQuery(context.Background(), q, a)
+	start := time.Now()
+	rows, err := c.Query(q, a...)
+	logQuery(context.Background(), q, a, start, err)
 	if err != nil {
 		return nil, err
 	}
@@ -251,7 +300,11 @@ func _Query(c Conn, query string, args ...interface{}) (*ModelRows, error) {
 
 func _QueryAll(c Conn, proto interface{}, query string, args ...interface{}) (interface{}, error) {
 	protos := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(reflect.TypeOf(proto))), 0, 0)
-	rows, err := c.Query(ConvertPH(query), expandArgs(args...)...)
+	q, a := ConvertPH(query, GetDialect()), expandArgs(args...)
+	beforeQuery(context.Background(), q, a)
+	start := time.Now()
+	rows, err := c.Query(q, a...)
+	logQuery(context.Background(), q, a, start, err)
 	if err != nil {
 		return nil, err
 	}
@@ -390,14 +443,18 @@ func QueryAll(proto interface{}, query string, args ...interface{}) (interface{}
 }
 
 func Begin(level string) (*ModelTx, error) {
-	tx, err := GetDB().Begin()
+	return BeginContext(context.Background(), level)
+}
+
+func BeginContext(ctx context.Context, level string) (*ModelTx, error) {
+	tx, err := GetDB().BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	if level == "" {
 		level = "READ COMMITTED"
 	}
-	_, err = tx.Exec(`SET TRANSACTION ISOLATION LEVEL ` + level)
+	_, err = tx.ExecContext(ctx, `SET TRANSACTION ISOLATION LEVEL `+level)
 	if err != nil {
 		return nil, err
 	}
@@ -407,12 +464,18 @@ func Begin(level string) (*ModelTx, error) {
 // Auto-retries and commits the block of code in f.
 // Any panic'd errors will be returned.
 func DoBegin(level string, f func(*ModelTx)) (retErr error) {
+	return DoBeginContext(context.Background(), level, f)
+}
+
+// Like DoBegin, but aborts the retry loop (returning ctx.Err()) instead of
+// spinning forever on ERR_SERIAL_TX once ctx is canceled or times out.
+func DoBeginContext(ctx context.Context, level string, f func(*ModelTx)) (retErr error) {
 	var tries = 0
 	for {
 		var retry = false
 		(func() {
 			// Start transaction
-			tx, err := Begin(level)
+			tx, err := BeginContext(ctx, level)
 			if err != nil {
 				retErr = err
 				retry = false
@@ -469,6 +532,9 @@ func DoBegin(level string, f func(*ModelTx)) (retErr error) {
 		})()
 
 		if retry {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			tries++
 			log.Printf("Retrying serializable transaction: try %v", tries)
 			continue
@@ -483,27 +549,6 @@ func DoBeginSerializable(f func(*ModelTx)) (retErr error) {
 	return DoBegin("SERIALIZABLE", f)
 }
 
-//-----------------------------------------------------------------------------
-// Null*
-
-type NullString string
-
-func (ns *NullString) Scan(value interface{}) error {
-	if value == nil {
-		*ns = NullString("")
-	} else {
-		*ns = NullString(string(value.([]uint8)))
-	}
-	return nil
-}
-
-type NullInt64 int64
-
-func (ni *NullInt64) Scan(value interface{}) error {
-	if value == nil {
-		*ni = NullInt64(0)
-	} else {
-		*ni = NullInt64(int64(value.(int64)))
-	}
-	return nil
+func DoBeginSerializableContext(ctx context.Context, f func(*ModelTx)) (retErr error) {
+	return DoBeginContext(ctx, "SERIALIZABLE", f)
 }
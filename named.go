@@ -0,0 +1,166 @@
+/*
+This file adds :name-style named-parameter binding on top of the ?-style
+placeholder support in adapter.go, similar to sqlx's named queries. Named
+queries run through the same beforeQuery/logQuery hooks as _Exec/_Query/
+_QueryRow in modeldb.go, so they show up in logs and BeforeQuery/AfterQuery
+hooks like any other query.
+*/
+
+package modeldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	. "github.com/jaekwon/pego"
+)
+
+// bindsFor turns arg into a name->value map for NAMED token resolution.
+// arg may be a map[string]interface{} or a struct/pointer-to-struct whose
+// fields carry `db` tags (resolved via GetModelInfo).
+func bindsFor(arg interface{}) map[string]interface{} {
+	if binds, ok := arg.(map[string]interface{}); ok {
+		return binds
+	}
+
+	modelInfo := GetModelInfo(arg)
+	if modelInfo == nil {
+		log.Panicf("Named query argument must be a map[string]interface{} or a struct, got %T", arg)
+	}
+
+	v := reflect.ValueOf(arg)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	binds := map[string]interface{}{}
+	for _, field := range modelInfo.Fields {
+		binds[field.Column] = v.FieldByName(field.Name).Interface()
+	}
+	return binds
+}
+
+// convertNamedPH rewrites the :name tokens in q into dialect's positional
+// placeholders, resolving each name against binds, and returns the
+// rewritten query along with the args in matching order. Named queries
+// take all their values from binds, so a bare ? placeholder (which
+// NamedExec/NamedQuery/NamedQueryRow have no way to supply a value for)
+// is rejected rather than silently emitted with a missing arg.
+func convertNamedPH(q string, dialect Dialect, binds map[string]interface{}) (string, []interface{}, error) {
+	r, err, _ := Match(phGrammar, q)
+	if err != nil {
+		return "", nil, err
+	}
+
+	index := 1
+	parts := []string{}
+	args := []interface{}{}
+	for _, item := range r.([]interface{}) {
+		s := item.(string)
+		switch {
+		case s == "?":
+			return "", nil, fmt.Errorf("modeldb: named queries don't support bare ? placeholders, got %q", q)
+		case strings.HasPrefix(s, ":"):
+			name := s[1:]
+			val, ok := binds[name]
+			if !ok {
+				return "", nil, fmt.Errorf("modeldb: no value bound for :%v", name)
+			}
+			parts = append(parts, dialect.Placeholder(index))
+			index++
+			args = append(args, val)
+		default:
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ""), args, nil
+}
+
+func _NamedExec(c Conn, dialect Dialect, query string, arg interface{}) (sql.Result, error) {
+	q, args, err := convertNamedPH(query, dialect, bindsFor(arg))
+	if err != nil {
+		return nil, err
+	}
+	beforeQuery(context.Background(), q, args)
+	start := time.Now()
+	res, err := c.Exec(q, args...)
+	logQuery(context.Background(), q, args, start, err)
+	return res, err
+}
+
+func _NamedQuery(c Conn, dialect Dialect, query string, arg interface{}) (*ModelRows, error) {
+	q, args, err := convertNamedPH(query, dialect, bindsFor(arg))
+	if err != nil {
+		return nil, err
+	}
+	beforeQuery(context.Background(), q, args)
+	start := time.Now()
+	rows, err := c.Query(q, args...)
+	logQuery(context.Background(), q, args, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelRows{rows}, nil
+}
+
+func _NamedQueryRow(c Conn, dialect Dialect, query string, arg interface{}) (*ModelRow, error) {
+	q, args, err := convertNamedPH(query, dialect, bindsFor(arg))
+	if err != nil {
+		return nil, err
+	}
+	beforeQuery(context.Background(), q, args)
+	start := time.Now()
+	row := c.QueryRow(q, args...)
+	logQuery(context.Background(), q, args, start, nil)
+	return &ModelRow{row}, nil
+}
+
+//-----------------------------------------------------------------------------
+// ModelDB
+
+func (mDB *ModelDB) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return _NamedExec(mDB.DB, GetDialect(), query, arg)
+}
+
+func (mDB *ModelDB) NamedQuery(query string, arg interface{}) (*ModelRows, error) {
+	return _NamedQuery(mDB.DB, GetDialect(), query, arg)
+}
+
+func (mDB *ModelDB) NamedQueryRow(query string, arg interface{}) (*ModelRow, error) {
+	return _NamedQueryRow(mDB.DB, GetDialect(), query, arg)
+}
+
+//-----------------------------------------------------------------------------
+// ModelTx
+
+func (mTx *ModelTx) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return _NamedExec(mTx.Tx, GetDialect(), query, arg)
+}
+
+func (mTx *ModelTx) NamedQuery(query string, arg interface{}) (*ModelRows, error) {
+	return _NamedQuery(mTx.Tx, GetDialect(), query, arg)
+}
+
+func (mTx *ModelTx) NamedQueryRow(query string, arg interface{}) (*ModelRow, error) {
+	return _NamedQueryRow(mTx.Tx, GetDialect(), query, arg)
+}
+
+//-----------------------------------------------------------------------------
+// Convenience methods
+
+func NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return _NamedExec(GetDB(), GetDialect(), query, arg)
+}
+
+func NamedQuery(query string, arg interface{}) (*ModelRows, error) {
+	return _NamedQuery(GetDB(), GetDialect(), query, arg)
+}
+
+func NamedQueryRow(query string, arg interface{}) (*ModelRow, error) {
+	return _NamedQueryRow(GetDB(), GetDialect(), query, arg)
+}
@@ -0,0 +1,129 @@
+/*
+This file handles scanning nullable columns (db:"col,null") into struct
+fields via a pluggable registry, instead of a hardcoded set of wrapper
+types. Fields whose type already implements sql.Scanner (sql.NullString,
+sql.NullInt64, sql.NullInt32, sql.NullFloat64, sql.NullBool, sql.NullTime,
+uuid.UUID, ...) or that database/sql already knows how to scan a NULL
+into ([]byte) need no entry here at all; the registry exists for plain Go
+types (string, int64, time.Time, ...) that aren't nullable on their own.
+*/
+
+package modeldb
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+)
+
+// NullScannerFactory builds a sql.Scanner that, when Scan'd, writes the
+// resulting value into dField (which is always addressable).
+type NullScannerFactory func(dField reflect.Value) sql.Scanner
+
+var nullScanners = map[reflect.Type]NullScannerFactory{}
+
+// RegisterNullScanner registers how to scan a nullable column into a
+// struct field of type t, for use with a db:"col,null" tag. Built-in
+// registrations cover string, int64, int32, float64, bool, and
+// time.Time; register your own for anything else (JSONB, arrays, enums).
+func RegisterNullScanner(t reflect.Type, factory NullScannerFactory) {
+	nullScanners[t] = factory
+}
+
+// fieldScanDest returns the value scanStruct should pass to Scan for
+// field, routing nullable fields through the registry when available.
+func fieldScanDest(field *ModelField, dField reflect.Value) interface{} {
+	if field.Null {
+		if factory, ok := nullScanners[field.Type]; ok {
+			return factory(dField)
+		}
+	}
+	return dField.Addr().Interface()
+}
+
+func init() {
+	RegisterNullScanner(reflect.TypeOf(""), func(dField reflect.Value) sql.Scanner {
+		return &nullStringField{dField}
+	})
+	RegisterNullScanner(reflect.TypeOf(int64(0)), func(dField reflect.Value) sql.Scanner {
+		return &nullInt64Field{dField}
+	})
+	RegisterNullScanner(reflect.TypeOf(int32(0)), func(dField reflect.Value) sql.Scanner {
+		return &nullInt32Field{dField}
+	})
+	RegisterNullScanner(reflect.TypeOf(float64(0)), func(dField reflect.Value) sql.Scanner {
+		return &nullFloat64Field{dField}
+	})
+	RegisterNullScanner(reflect.TypeOf(false), func(dField reflect.Value) sql.Scanner {
+		return &nullBoolField{dField}
+	})
+	RegisterNullScanner(reflect.TypeOf(time.Time{}), func(dField reflect.Value) sql.Scanner {
+		return &nullTimeField{dField}
+	})
+}
+
+type nullStringField struct{ dField reflect.Value }
+
+func (f *nullStringField) Scan(value interface{}) error {
+	var ns sql.NullString
+	if err := ns.Scan(value); err != nil {
+		return err
+	}
+	f.dField.SetString(ns.String)
+	return nil
+}
+
+type nullInt64Field struct{ dField reflect.Value }
+
+func (f *nullInt64Field) Scan(value interface{}) error {
+	var ni sql.NullInt64
+	if err := ni.Scan(value); err != nil {
+		return err
+	}
+	f.dField.SetInt(ni.Int64)
+	return nil
+}
+
+type nullInt32Field struct{ dField reflect.Value }
+
+func (f *nullInt32Field) Scan(value interface{}) error {
+	var ni sql.NullInt32
+	if err := ni.Scan(value); err != nil {
+		return err
+	}
+	f.dField.SetInt(int64(ni.Int32))
+	return nil
+}
+
+type nullFloat64Field struct{ dField reflect.Value }
+
+func (f *nullFloat64Field) Scan(value interface{}) error {
+	var nf sql.NullFloat64
+	if err := nf.Scan(value); err != nil {
+		return err
+	}
+	f.dField.SetFloat(nf.Float64)
+	return nil
+}
+
+type nullBoolField struct{ dField reflect.Value }
+
+func (f *nullBoolField) Scan(value interface{}) error {
+	var nb sql.NullBool
+	if err := nb.Scan(value); err != nil {
+		return err
+	}
+	f.dField.SetBool(nb.Bool)
+	return nil
+}
+
+type nullTimeField struct{ dField reflect.Value }
+
+func (f *nullTimeField) Scan(value interface{}) error {
+	var nt sql.NullTime
+	if err := nt.Scan(value); err != nil {
+		return err
+	}
+	f.dField.Set(reflect.ValueOf(nt.Time))
+	return nil
+}
@@ -0,0 +1,101 @@
+package modeldb
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type scannerTestStruct struct {
+	Name string
+	Age  int64
+}
+
+func TestFieldScanDestUsesRegistryForNullFields(t *testing.T) {
+	s := &scannerTestStruct{}
+	v := reflect.ValueOf(s).Elem()
+
+	field := &ModelField{Column: "name", Null: true}
+	field.Type = v.FieldByName("Name").Type()
+
+	dest := fieldScanDest(field, v.FieldByName("Name"))
+	scanner, ok := dest.(*nullStringField)
+	if !ok {
+		t.Fatalf("fieldScanDest returned %T, want *nullStringField", dest)
+	}
+	if err := scanner.Scan(nil); err != nil {
+		t.Fatalf("unexpected error scanning nil: %v", err)
+	}
+	if s.Name != "" {
+		t.Errorf("Name = %q after scanning nil, want empty string", s.Name)
+	}
+	if err := scanner.Scan("bob"); err != nil {
+		t.Fatalf("unexpected error scanning value: %v", err)
+	}
+	if s.Name != "bob" {
+		t.Errorf("Name = %q, want %q", s.Name, "bob")
+	}
+}
+
+func TestFieldScanDestSkipsRegistryForNonNullFields(t *testing.T) {
+	s := &scannerTestStruct{}
+	v := reflect.ValueOf(s).Elem()
+
+	field := &ModelField{Column: "age", Null: false}
+	field.Type = v.FieldByName("Age").Type()
+
+	dest := fieldScanDest(field, v.FieldByName("Age"))
+	if _, ok := dest.(*nullInt64Field); ok {
+		t.Errorf("fieldScanDest routed a non-null field through the registry")
+	}
+	if _, ok := dest.(*int64); !ok {
+		t.Errorf("fieldScanDest returned %T, want *int64", dest)
+	}
+}
+
+func TestNullTimeFieldScan(t *testing.T) {
+	var s struct{ At time.Time }
+	v := reflect.ValueOf(&s).Elem()
+	f := &nullTimeField{v.FieldByName("At")}
+
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := f.Scan(want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !s.At.Equal(want) {
+		t.Errorf("At = %v, want %v", s.At, want)
+	}
+}
+
+func TestNullBoolFieldScan(t *testing.T) {
+	var s struct{ Active bool }
+	v := reflect.ValueOf(&s).Elem()
+	f := &nullBoolField{v.FieldByName("Active")}
+
+	if err := f.Scan(nil); err != nil {
+		t.Fatalf("unexpected error scanning nil: %v", err)
+	}
+	if s.Active != false {
+		t.Errorf("Active = %v after scanning nil, want false", s.Active)
+	}
+	if err := f.Scan(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Active != true {
+		t.Errorf("Active = %v, want true", s.Active)
+	}
+}
+
+func TestRegisterNullScanner(t *testing.T) {
+	type myEnum int
+	t_ := reflect.TypeOf(myEnum(0))
+	RegisterNullScanner(t_, func(dField reflect.Value) sql.Scanner {
+		return &nullInt64Field{dField}
+	})
+	defer delete(nullScanners, t_)
+
+	if _, ok := nullScanners[t_]; !ok {
+		t.Error("RegisterNullScanner did not register the factory")
+	}
+}